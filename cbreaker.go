@@ -1,11 +1,17 @@
 package cbreaker
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// ErrTooManyRequests is returned by Try when the circuit is HalfOpen and the
+// number of in-flight probe requests already reached WithMaxRequests.
+var ErrTooManyRequests = errors.New("cbreaker: too many requests")
+
 // State represents circuit state.
 type State uint32
 
@@ -36,6 +42,84 @@ func (s State) String() string {
 // For example it can be used for logging.
 type StateChangeCallback func(current, new State)
 
+// Result represents the outcome of a single call made through Try/TryContext,
+// as reported to an Observer.
+type Result int
+
+const (
+	// ResultSuccess indicates the callback was invoked and succeeded.
+	ResultSuccess Result = iota
+	// ResultFailure indicates the callback was invoked and failed.
+	ResultFailure
+	// ResultShortCircuit indicates the callback was not invoked because the
+	// circuit was Open or HalfOpen and over its MaxRequests cap.
+	ResultShortCircuit
+)
+
+// String returns the Result name, matching the "result" label values emitted
+// by the metrics subpackage.
+func (r Result) String() string {
+	switch r {
+	case ResultSuccess:
+		return "success"
+	case ResultFailure:
+		return "failure"
+	case ResultShortCircuit:
+		return "short_circuit"
+	default:
+		return ""
+	}
+}
+
+// Observer receives circuit breaker lifecycle events. It generalizes
+// StateChangeCallback so instrumentation such as the metrics subpackage's
+// Prometheus collector, or an OpenTelemetry span emitter, can be plugged in
+// without wrapping Try/TryContext.
+type Observer interface {
+	// OnRequest is called once per Try/TryContext call with its outcome. A
+	// TryContext call whose ctx is already done returns ctx.Err() without
+	// being reported.
+	OnRequest(result Result)
+	// OnStateChange is called whenever the circuit transitions between states.
+	OnStateChange(from, to State)
+	// OnShortCircuit is called whenever a call is rejected without invoking
+	// the callback, i.e. while Open or over MaxRequests while HalfOpen.
+	OnShortCircuit()
+}
+
+// Counts holds the numbers of requests and their successes/failures observed
+// by a Breaker while Closed. It is reset whenever the circuit leaves Closed
+// (open/close transition) or when `interval` elapses, whichever comes first.
+type Counts struct {
+	Requests             uint32
+	TotalSuccesses       uint32
+	TotalFailures        uint32
+	ConsecutiveSuccesses uint32
+	ConsecutiveFailures  uint32
+}
+
+func (c *Counts) onSuccess() {
+	c.Requests++
+	c.TotalSuccesses++
+	c.ConsecutiveSuccesses++
+	c.ConsecutiveFailures = 0
+}
+
+func (c *Counts) onFailure() {
+	c.Requests++
+	c.TotalFailures++
+	c.ConsecutiveFailures++
+	c.ConsecutiveSuccesses = 0
+}
+
+func (c *Counts) clear() {
+	*c = Counts{}
+}
+
+// TripFunc decides whether a Breaker should become Open given the Counts
+// accumulated so far while Closed. See WithTripFunc.
+type TripFunc func(Counts) bool
+
 // Breaker is a default circuit breaker implementation.
 type Breaker[T any] struct {
 	state uint32
@@ -54,6 +138,23 @@ type Breaker[T any] struct {
 	retryThreshold uint32
 
 	stateChangeCallback StateChangeCallback
+
+	tripFunc     TripFunc
+	isSuccessful func(error) bool
+	interval     time.Duration
+
+	counts    Counts
+	lastClear time.Time
+
+	maxRequests      uint32
+	halfOpenInFlight uint32
+	successThreshold uint32
+	currentSuccess   uint32
+
+	callTimeout time.Duration
+
+	name      string
+	observers []Observer
 }
 
 type configuration struct {
@@ -61,6 +162,14 @@ type configuration struct {
 	openTimeout         time.Duration
 	retryThreshold      uint32
 	stateChangeCallback StateChangeCallback
+	tripFunc            TripFunc
+	isSuccessful        func(error) bool
+	interval            time.Duration
+	maxRequests         uint32
+	successThreshold    uint32
+	callTimeout         time.Duration
+	name                string
+	observers           []Observer
 }
 
 // Option modifies Breaker configuration.
@@ -94,6 +203,80 @@ func WithStateChangeCallback(callback StateChangeCallback) Option {
 	}
 }
 
+// WithTripFunc sets a TripFunc consulted while Closed to decide whether the
+// circuit should become Open, based on the rolling Counts observed so far.
+// When set, it takes precedence over WithThreshold's failure-count check.
+func WithTripFunc(tripFunc TripFunc) Option {
+	return func(c *configuration) {
+		c.tripFunc = tripFunc
+	}
+}
+
+// WithInterval sets the cyclic period by which the Closed-state Counts are
+// cleared. If interval is 0 (default) Counts are never cleared on a timer,
+// only on a state transition.
+func WithInterval(interval time.Duration) Option {
+	return func(c *configuration) {
+		c.interval = interval
+	}
+}
+
+// WithIsSuccessful sets the predicate used to classify a callback error as a
+// failure for tripping purposes. By default any non-nil error is a failure;
+// use this to treat e.g. context cancellation or expected business errors as
+// successes.
+func WithIsSuccessful(isSuccessful func(error) bool) Option {
+	return func(c *configuration) {
+		c.isSuccessful = isSuccessful
+	}
+}
+
+// WithMaxRequests caps the number of concurrent probe requests admitted to
+// the real callback while HalfOpen. Callers beyond the cap receive
+// ErrTooManyRequests instead of invoking callback. Defaults to 1, i.e. a
+// single probe at a time; a value of 0 is treated the same as 1, matching
+// the convention used by other circuit breaker libraries.
+func WithMaxRequests(maxRequests uint32) Option {
+	return func(c *configuration) {
+		c.maxRequests = maxRequests
+	}
+}
+
+// WithSuccessThreshold sets the number of consecutive successes required
+// while HalfOpen before the circuit becomes Closed. Defaults to 1, matching
+// the previous behaviour where a single success closed the circuit.
+func WithSuccessThreshold(successThreshold uint32) Option {
+	return func(c *configuration) {
+		c.successThreshold = successThreshold
+	}
+}
+
+// WithCallTimeout wraps every TryContext callback invocation in a context
+// derived from the caller's with the given timeout. A deadline-exceeded
+// error is treated as a failure for tripping purposes unless overridden by
+// WithIsSuccessful.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(c *configuration) {
+		c.callTimeout = timeout
+	}
+}
+
+// WithName sets a name for the Breaker, used to label emitted metrics (see
+// the metrics subpackage) and otherwise distinguish it in observability tooling.
+func WithName(name string) Option {
+	return func(c *configuration) {
+		c.name = name
+	}
+}
+
+// WithObserver registers an Observer to receive lifecycle events from
+// construction onward. Use Breaker.Observe to attach one after construction.
+func WithObserver(observer Observer) Option {
+	return func(c *configuration) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
 // NewBreaker returns circuit breaker object.
 func NewBreaker[T any](opts ...Option) *Breaker[T] {
 	cfg := &configuration{
@@ -101,10 +284,15 @@ func NewBreaker[T any](opts ...Option) *Breaker[T] {
 		openTimeout:         time.Minute,
 		retryThreshold:      1,
 		stateChangeCallback: nil,
+		successThreshold:    1,
+		maxRequests:         1,
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if cfg.maxRequests == 0 {
+		cfg.maxRequests = 1
+	}
 	return &Breaker[T]{
 		state:               1,
 		threshold:           cfg.threshold,
@@ -114,6 +302,15 @@ func NewBreaker[T any](opts ...Option) *Breaker[T] {
 		currentRetry:        0,
 		retryThreshold:      cfg.retryThreshold,
 		stateChangeCallback: cfg.stateChangeCallback,
+		tripFunc:            cfg.tripFunc,
+		isSuccessful:        cfg.isSuccessful,
+		interval:            cfg.interval,
+		lastClear:           time.Now(),
+		maxRequests:         cfg.maxRequests,
+		successThreshold:    cfg.successThreshold,
+		callTimeout:         cfg.callTimeout,
+		name:                cfg.name,
+		observers:           cfg.observers,
 	}
 }
 
@@ -121,33 +318,71 @@ func NewBreaker[T any](opts ...Option) *Breaker[T] {
 // After `openTimeout` expires the circuit becomes half-open and will retry callback until
 // success or after `retryThreshold` is reached. In case of success it will become closed, otherwise it becomes open.
 func (b *Breaker[T]) Try(callback func() (T, error)) (T, error) {
+	return b.execute(context.Background(), func(context.Context) (T, error) {
+		return callback()
+	})
+}
+
+// TryContext works like Try but propagates ctx into callback and short-circuits
+// with ctx.Err() without calling callback if ctx is already done. If
+// WithCallTimeout is configured, callback additionally receives a context
+// derived from ctx bounded by that timeout.
+func (b *Breaker[T]) TryContext(ctx context.Context, callback func(context.Context) (T, error)) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var result T
+		return result, err
+	}
+	return b.execute(ctx, callback)
+}
+
+func (b *Breaker[T]) execute(ctx context.Context, callback func(context.Context) (T, error)) (T, error) {
 	state := b.State()
 	switch state {
 	case StateClosed:
-		result, err := callback()
-		if err == nil {
-			return result, nil
+		b.maybeClearCounts()
+		result, err := b.call(ctx, callback)
+		success := b.isSuccess(err)
+		b.recordResult(success)
+		if success {
+			b.notifyRequest(ResultSuccess)
+			return result, err
 		}
+		b.notifyRequest(ResultFailure)
 		b.try()
-		if b.shouldOpen() {
-			b.openCircuit(result, err)
+		if b.shouldOpen(StateClosed) {
+			b.openCircuit(StateClosed, result, err)
 		}
 		return result, err
 	case StateOpen:
 		if b.shouldHalfOpen() {
 			b.halfOpenCircuit()
 		}
+		b.notifyRequest(ResultShortCircuit)
+		b.notifyShortCircuit()
 		res, err := b.getPreviousResult()
 		return res, err
 	case StateHalfOpen:
-		result, err := callback()
-		if err == nil {
-			b.closeCircuit()
-			return result, nil
+		if atomic.AddUint32(&b.halfOpenInFlight, 1) > b.maxRequests {
+			atomic.AddUint32(&b.halfOpenInFlight, ^uint32(0))
+			b.notifyRequest(ResultShortCircuit)
+			b.notifyShortCircuit()
+			var result T
+			return result, ErrTooManyRequests
+		}
+		defer atomic.AddUint32(&b.halfOpenInFlight, ^uint32(0))
+
+		result, err := b.call(ctx, callback)
+		if b.isSuccess(err) {
+			b.notifyRequest(ResultSuccess)
+			if b.onHalfOpenSuccess() {
+				b.closeCircuit()
+			}
+			return result, err
 		}
+		b.notifyRequest(ResultFailure)
 		b.retry()
-		if b.shouldOpen() {
-			b.openCircuit(result, err)
+		if b.shouldOpen(StateHalfOpen) {
+			b.openCircuit(StateHalfOpen, result, err)
 		}
 		return result, err
 	default:
@@ -156,48 +391,179 @@ func (b *Breaker[T]) Try(callback func() (T, error)) (T, error) {
 	}
 }
 
+// call invokes callback with ctx, bounding it with WithCallTimeout if configured.
+func (b *Breaker[T]) call(ctx context.Context, callback func(context.Context) (T, error)) (T, error) {
+	if b.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.callTimeout)
+		defer cancel()
+	}
+	return callback(ctx)
+}
+
 // State returns corcuit breaker current State.
 func (b *Breaker[T]) State() State {
 	return State(atomic.LoadUint32(&b.state))
 }
 
-func (b *Breaker[T]) shouldOpen() bool {
-	return b.State() == StateClosed && atomic.LoadUint32(&b.currentTry) == b.threshold ||
-		b.State() == StateHalfOpen && atomic.LoadUint32(&b.currentRetry) == b.retryThreshold
+// Name returns the name this Breaker was constructed with via WithName, or
+// the empty string if none was set.
+func (b *Breaker[T]) Name() string {
+	return b.name
+}
+
+// Observe registers an additional Observer to receive lifecycle events. It
+// may be called after NewBreaker, e.g. to attach a metrics.PromCollector.
+func (b *Breaker[T]) Observe(observer Observer) {
+	b.mu.Lock()
+	b.observers = append(b.observers, observer)
+	b.mu.Unlock()
+}
+
+func (b *Breaker[T]) snapshotObservers() []Observer {
+	b.mu.RLock()
+	observers := b.observers
+	b.mu.RUnlock()
+	return observers
+}
+
+func (b *Breaker[T]) notifyRequest(result Result) {
+	for _, observer := range b.snapshotObservers() {
+		observer.OnRequest(result)
+	}
+}
+
+func (b *Breaker[T]) notifyShortCircuit() {
+	for _, observer := range b.snapshotObservers() {
+		observer.OnShortCircuit()
+	}
+}
+
+// shouldOpen reports whether the circuit should trip to Open, given the
+// state the caller observed itself operating under (StateClosed or
+// StateHalfOpen). Counters are compared with >=, not ==, since concurrent
+// callers can push them past the configured threshold in a single jump.
+func (b *Breaker[T]) shouldOpen(state State) bool {
+	switch state {
+	case StateClosed:
+		if b.tripFunc != nil {
+			return b.tripFunc(b.snapshotCounts())
+		}
+		return atomic.LoadUint32(&b.currentTry) >= b.threshold
+	case StateHalfOpen:
+		return atomic.LoadUint32(&b.currentRetry) >= b.retryThreshold
+	default:
+		return false
+	}
 }
 
 func (b *Breaker[T]) try() {
 	atomic.AddUint32(&b.currentTry, 1)
 }
 
-func (b *Breaker[T]) changeState(desired State) {
-	current := b.State()
-	atomic.StoreUint32(&b.state, uint32(desired))
-	b.recordStateTransition(current, desired)
+// isSuccess classifies err using the configured IsSuccessful predicate,
+// defaulting to treating any non-nil error as a failure.
+func (b *Breaker[T]) isSuccess(err error) bool {
+	if b.isSuccessful != nil {
+		return b.isSuccessful(err)
+	}
+	return err == nil
+}
+
+// recordResult updates the rolling Counts for the outcome of a Closed-state call.
+func (b *Breaker[T]) recordResult(success bool) {
+	b.mu.Lock()
+	if success {
+		b.counts.onSuccess()
+	} else {
+		b.counts.onFailure()
+	}
+	b.mu.Unlock()
+}
+
+func (b *Breaker[T]) snapshotCounts() Counts {
+	b.mu.RLock()
+	counts := b.counts
+	b.mu.RUnlock()
+	return counts
+}
+
+// maybeClearCounts clears the rolling Counts once `interval` has elapsed
+// since they were last cleared. It is a no-op when no interval is configured.
+func (b *Breaker[T]) maybeClearCounts() {
+	if b.interval <= 0 {
+		return
+	}
+	b.mu.Lock()
+	if time.Now().After(b.lastClear.Add(b.interval)) {
+		b.counts.clear()
+		b.lastClear = time.Now()
+	}
+	b.mu.Unlock()
+}
+
+// changeStateCAS transitions state from -> to only if the circuit is still
+// in `from`, recording the transition on success. It is the single critical
+// section that arbitrates between concurrent callers racing to trip or
+// recover the circuit: only the goroutine that wins the compare-and-swap
+// performs the transition's side effects.
+func (b *Breaker[T]) changeStateCAS(from, to State) bool {
+	if !atomic.CompareAndSwapUint32(&b.state, uint32(from), uint32(to)) {
+		return false
+	}
+	b.recordStateTransition(from, to)
+	return true
+}
+
+// changeStateForce unconditionally transitions to `to`, for admin operations
+// (Reset, ForceOpen) that must take effect regardless of the current state.
+func (b *Breaker[T]) changeStateForce(to State) {
+	current := State(atomic.SwapUint32(&b.state, uint32(to)))
+	if current != to {
+		b.recordStateTransition(current, to)
+	}
 }
 
 func (b *Breaker[T]) recordStateTransition(current, desired State) {
 	if b.stateChangeCallback != nil {
 		b.stateChangeCallback(current, desired)
 	}
+	for _, observer := range b.snapshotObservers() {
+		observer.OnStateChange(current, desired)
+	}
 }
 
-func (b *Breaker[T]) openCircuit(result T, err error) {
-	b.changeState(StateOpen)
+// openCircuit attempts to trip the circuit from `from` to Open, caching
+// result/err for subsequent short-circuited callers. If another goroutine
+// already won the transition, this is a no-op.
+func (b *Breaker[T]) openCircuit(from State, result T, err error) {
+	if !b.changeStateCAS(from, StateOpen) {
+		return
+	}
 
 	b.mu.Lock()
 	b.openResult = result
 	b.openErr = err
 	b.openTime = time.Now()
+	b.counts.clear()
 	b.mu.Unlock()
 }
 
 func (b *Breaker[T]) shouldHalfOpen() bool {
-	return time.Now().After(b.openTime.Add(b.openTimeout))
+	b.mu.RLock()
+	openTime := b.openTime
+	b.mu.RUnlock()
+	return time.Now().After(openTime.Add(b.openTimeout))
 }
 
+// halfOpenCircuit attempts to move the circuit from Open to HalfOpen. If
+// another goroutine already won the transition, this is a no-op.
 func (b *Breaker[T]) halfOpenCircuit() {
-	b.changeState(StateHalfOpen)
+	if !b.changeStateCAS(StateOpen, StateHalfOpen) {
+		return
+	}
+	atomic.StoreUint32(&b.currentRetry, 0)
+	atomic.StoreUint32(&b.currentSuccess, 0)
 }
 
 func (b *Breaker[T]) getPreviousResult() (T, error) {
@@ -207,22 +573,79 @@ func (b *Breaker[T]) getPreviousResult() (T, error) {
 	return res, err
 }
 
+// retry records a HalfOpen failure. It resets currentSuccess so that only
+// *consecutive* HalfOpen successes count towards WithSuccessThreshold,
+// mirroring how Counts.onFailure resets ConsecutiveSuccesses.
 func (b *Breaker[T]) retry() {
 	atomic.AddUint32(&b.currentRetry, 1)
+	atomic.StoreUint32(&b.currentSuccess, 0)
 }
 
-func (b *Breaker[T]) closeCircuit() {
+// onHalfOpenSuccess records a HalfOpen success and reports whether enough
+// consecutive successes have accumulated to close the circuit.
+func (b *Breaker[T]) onHalfOpenSuccess() bool {
+	return atomic.AddUint32(&b.currentSuccess, 1) >= b.successThreshold
+}
+
+// clearCachedResult drops any cached Open result/error and resets the
+// rolling Counts, under the single critical section guarding them.
+func (b *Breaker[T]) clearCachedResult() {
 	b.mu.Lock()
 	var res T
 	b.openResult = res
 	b.openErr = nil
 	b.openTime = time.Time{}
+	b.counts.clear()
+	b.lastClear = time.Now()
 	b.mu.Unlock()
+}
 
+func (b *Breaker[T]) resetCounters() {
 	atomic.StoreUint32(&b.currentTry, 0)
 	atomic.StoreUint32(&b.currentRetry, 0)
+	atomic.StoreUint32(&b.currentSuccess, 0)
+}
 
-	b.changeState(StateClosed)
+// closeCircuit attempts to move the circuit from HalfOpen to Closed. If
+// another goroutine already won the transition, this is a no-op.
+func (b *Breaker[T]) closeCircuit() {
+	if !b.changeStateCAS(StateHalfOpen, StateClosed) {
+		return
+	}
+	b.clearCachedResult()
+	b.resetCounters()
+}
+
+// Reset forces the circuit Closed and zeroes all counters and cached
+// results, as if newly constructed. Intended for operator intervention, e.g.
+// after manually confirming a dependency has recovered.
+//
+// halfOpenInFlight is deliberately left untouched: it is only ever adjusted
+// in increment/decrement pairs by callers that observed HalfOpen in
+// execute(), regardless of what the circuit transitions to afterwards, so it
+// settles back to 0 on its own as those calls finish. Zeroing it here would
+// race with their deferred decrements and could underflow it permanently.
+func (b *Breaker[T]) Reset() {
+	b.clearCachedResult()
+	b.resetCounters()
+	b.changeStateForce(StateClosed)
+}
+
+// ForceOpen forces the circuit Open, caching err (and the zero value of T)
+// as the result returned to short-circuited callers. Intended for operator
+// intervention, e.g. pre-emptively shedding load from a dependency known to
+// be down. The circuit behaves as any Open circuit thereafter, becoming
+// HalfOpen after openTimeout.
+func (b *Breaker[T]) ForceOpen(err error) {
+	b.mu.Lock()
+	var res T
+	b.openResult = res
+	b.openErr = err
+	b.openTime = time.Now()
+	b.counts.clear()
+	b.mu.Unlock()
+
+	b.changeStateForce(StateOpen)
 }
 
 // NoRetBreaker is a circuit breaker that returns only an error.
@@ -246,6 +669,16 @@ func (b *NoRetBreaker) Try(callback func() error) error {
 	return err
 }
 
+// TryContext works like Try but propagates ctx into callback and short-circuits
+// with ctx.Err() without calling callback if ctx is already done, same as
+// Breaker.TryContext.
+func (b *NoRetBreaker) TryContext(ctx context.Context, callback func(context.Context) error) error {
+	_, err := b.breaker.TryContext(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, callback(ctx)
+	})
+	return err
+}
+
 // State returns current state.
 func (b *NoRetBreaker) State() State {
 	return b.breaker.State()
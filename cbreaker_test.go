@@ -1,7 +1,10 @@
 package cbreaker_test
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -85,4 +88,305 @@ func TestCbreaker(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, cbreaker.StateClosed, breaker.State())
 	})
+
+	t.Run("Reset and ForceOpen", func(t *testing.T) {
+		t.Parallel()
+
+		breaker := cbreaker.NewBreaker[int](cbreaker.WithThreshold(1))
+
+		_, err := breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+
+		breaker.Reset()
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+		_, err = breaker.Try(func() (int, error) { return 42, nil })
+		require.NoError(t, err)
+
+		errForced := errors.New("forced open")
+		breaker.ForceOpen(errForced)
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+		_, err = breaker.Try(func() (int, error) {
+			t.Fatal("callback must not run while forced open")
+			return 0, nil
+		})
+		require.ErrorIs(t, err, errForced)
+	})
+
+	t.Run("Reset does not corrupt halfOpenInFlight for probes started before it", func(t *testing.T) {
+		t.Parallel()
+
+		breaker := cbreaker.NewBreaker[int](
+			cbreaker.WithThreshold(1),
+			cbreaker.WithOpenTimeout(time.Millisecond*10),
+			cbreaker.WithMaxRequests(2),
+		)
+
+		_, err := breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		time.Sleep(time.Millisecond * 20)
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = breaker.Try(func() (int, error) {
+					started <- struct{}{}
+					<-release
+					return 0, nil
+				})
+			}()
+		}
+		<-started
+		<-started
+
+		// Reset races with the two in-flight probes above; their deferred
+		// halfOpenInFlight decrements must not underflow the counter.
+		breaker.Reset()
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+		time.Sleep(time.Millisecond * 20)
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		_, err = breaker.Try(func() (int, error) { return 0, nil })
+		require.NoError(t, err)
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+	})
+
+	t.Run("WithSuccessThreshold requires consecutive HalfOpen successes", func(t *testing.T) {
+		t.Parallel()
+
+		breaker := cbreaker.NewBreaker[int](
+			cbreaker.WithThreshold(1),
+			cbreaker.WithOpenTimeout(time.Millisecond*10),
+			cbreaker.WithRetryThreshold(5),
+			cbreaker.WithSuccessThreshold(2),
+		)
+
+		_, err := breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		time.Sleep(time.Millisecond * 20)
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		// One success short of the threshold: must not close yet.
+		_, err = breaker.Try(func() (int, error) { return 0, nil })
+		require.NoError(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		// A failure breaks the streak, so a lone following success must not close it.
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		_, err = breaker.Try(func() (int, error) { return 0, nil })
+		require.NoError(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		_, err = breaker.Try(func() (int, error) { return 0, nil })
+		require.NoError(t, err)
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+	})
+
+	t.Run("race stress: single Closed->Open transition per failure burst", func(t *testing.T) {
+		t.Parallel()
+
+		var transitions int32
+		breaker := cbreaker.NewBreaker[int](
+			cbreaker.WithThreshold(5),
+			cbreaker.WithStateChangeCallback(func(current, newState cbreaker.State) {
+				if current == cbreaker.StateClosed && newState == cbreaker.StateOpen {
+					atomic.AddInt32(&transitions, 1)
+				}
+			}),
+		)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = breaker.Try(func() (int, error) {
+					return 0, errors.New("error")
+				})
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+		require.Equal(t, int32(1), atomic.LoadInt32(&transitions))
+	})
+
+	t.Run("TryContext cancellation and WithCallTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		breaker := cbreaker.NewBreaker[int](cbreaker.WithCallTimeout(time.Millisecond * 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := false
+		_, err := breaker.TryContext(ctx, func(context.Context) (int, error) {
+			called = true
+			return 0, nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, called)
+
+		_, err = breaker.TryContext(context.Background(), func(callCtx context.Context) (int, error) {
+			<-callCtx.Done()
+			return 0, callCtx.Err()
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("HalfOpen caps concurrent probes even without WithMaxRequests", func(t *testing.T) {
+		t.Parallel()
+
+		breaker := cbreaker.NewBreaker[int](
+			cbreaker.WithThreshold(1),
+			cbreaker.WithOpenTimeout(time.Millisecond*10),
+		)
+
+		_, err := breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+
+		time.Sleep(time.Millisecond * 20)
+
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		probeDone := make(chan error, 1)
+		go func() {
+			_, probeErr := breaker.Try(func() (int, error) {
+				close(started)
+				<-release
+				return 0, nil
+			})
+			probeDone <- probeErr
+		}()
+		<-started
+
+		const contenders = 19
+		var wg sync.WaitGroup
+		results := make([]error, contenders)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, results[i] = breaker.Try(func() (int, error) {
+					t.Error("real callback must not run while another HalfOpen probe is in flight")
+					return 0, nil
+				})
+			}(i)
+		}
+		wg.Wait()
+
+		for _, resultErr := range results {
+			require.ErrorIs(t, resultErr, cbreaker.ErrTooManyRequests)
+		}
+
+		close(release)
+		require.NoError(t, <-probeDone)
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+	})
+
+	t.Run("WithMaxRequests and WithSuccessThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		breaker := cbreaker.NewBreaker[int](
+			cbreaker.WithThreshold(1),
+			cbreaker.WithOpenTimeout(time.Millisecond*10),
+			cbreaker.WithMaxRequests(1),
+			cbreaker.WithSuccessThreshold(2),
+		)
+
+		_, err := breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+
+		time.Sleep(time.Millisecond * 20)
+
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		probeDone := make(chan error, 1)
+		go func() {
+			_, probeErr := breaker.Try(func() (int, error) {
+				close(started)
+				<-release
+				return 0, nil
+			})
+			probeDone <- probeErr
+		}()
+		<-started
+
+		_, err = breaker.Try(func() (int, error) { return 0, nil })
+		require.ErrorIs(t, err, cbreaker.ErrTooManyRequests)
+
+		close(release)
+		require.NoError(t, <-probeDone)
+		require.Equal(t, cbreaker.StateHalfOpen, breaker.State())
+
+		_, err = breaker.Try(func() (int, error) { return 0, nil })
+		require.NoError(t, err)
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+	})
+
+	t.Run("WithTripFunc and WithIsSuccessful", func(t *testing.T) {
+		t.Parallel()
+
+		errBusiness := errors.New("business error")
+
+		breaker := cbreaker.NewBreaker[int](
+			cbreaker.WithOpenTimeout(time.Minute),
+			cbreaker.WithIsSuccessful(func(err error) bool {
+				return err == nil || errors.Is(err, errBusiness)
+			}),
+			cbreaker.WithTripFunc(func(counts cbreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= 2
+			}),
+		)
+
+		// Business errors are not failures and must not count towards tripping,
+		// no matter how many of them occur in a row.
+		for i := 0; i < 10; i++ {
+			_, err := breaker.Try(func() (int, error) {
+				return 0, errBusiness
+			})
+			require.ErrorIs(t, err, errBusiness)
+		}
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+
+		// Two consecutive real failures should trip the breaker.
+		_, err := breaker.Try(func() (int, error) { return 0, errors.New("real error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateClosed, breaker.State())
+
+		_, err = breaker.Try(func() (int, error) { return 0, errors.New("real error") })
+		require.Error(t, err)
+		require.Equal(t, cbreaker.StateOpen, breaker.State())
+	})
 }
@@ -0,0 +1,76 @@
+package cbreaker
+
+import (
+	"context"
+	"sync"
+)
+
+// Group maintains an independent Breaker[T] per key, so that a failing
+// downstream does not trip the circuit for unrelated keys, e.g. per host,
+// route, or tenant. Breakers are created lazily on first use of a key, all
+// constructed with the Options passed to NewGroup.
+type Group[T any] struct {
+	opts     []Option
+	breakers sync.Map // string -> *Breaker[T]
+}
+
+// NewGroup returns a Group whose per-key Breakers are all constructed with opts.
+func NewGroup[T any](opts ...Option) *Group[T] {
+	return &Group[T]{opts: opts}
+}
+
+func (g *Group[T]) breaker(key string) *Breaker[T] {
+	if b, ok := g.breakers.Load(key); ok {
+		return b.(*Breaker[T])
+	}
+	b, _ := g.breakers.LoadOrStore(key, NewBreaker[T](g.opts...))
+	return b.(*Breaker[T])
+}
+
+// Breaker returns the *Breaker[T] registered for key, creating one lazily on
+// first use with the Options passed to NewGroup. Use it to reach
+// per-key functionality Group does not itself forward, e.g. Observe to attach
+// per-key metrics, or ForceOpen.
+func (g *Group[T]) Breaker(key string) *Breaker[T] {
+	return g.breaker(key)
+}
+
+// Try routes callback through the Breaker registered for key, creating one
+// lazily on first use.
+func (g *Group[T]) Try(key string, callback func() (T, error)) (T, error) {
+	return g.breaker(key).Try(callback)
+}
+
+// TryContext works like Try but propagates ctx into callback and
+// short-circuits with ctx.Err() without calling callback if ctx is already
+// done, same as Breaker.TryContext.
+func (g *Group[T]) TryContext(ctx context.Context, key string, callback func(context.Context) (T, error)) (T, error) {
+	return g.breaker(key).TryContext(ctx, callback)
+}
+
+// State returns the current State of the Breaker for key, or StateClosed if
+// key has not been used yet.
+func (g *Group[T]) State(key string) State {
+	if b, ok := g.breakers.Load(key); ok {
+		return b.(*Breaker[T]).State()
+	}
+	return StateClosed
+}
+
+// Reset forces the Breaker for key Closed and zeroes its counters. It is a
+// no-op if key has not been used yet.
+func (g *Group[T]) Reset(key string) {
+	if b, ok := g.breakers.Load(key); ok {
+		b.(*Breaker[T]).Reset()
+	}
+}
+
+// Snapshot returns the current State of every Breaker the Group has created.
+func (g *Group[T]) Snapshot() map[string]State {
+	snapshot := make(map[string]State)
+	g.breakers.Range(func(key, value any) bool {
+		snapshot[key.(string)] = value.(*Breaker[T]).State()
+		return true
+	})
+	return snapshot
+}
@@ -0,0 +1,67 @@
+package cbreaker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/piotrpersona/cbreaker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	group := cbreaker.NewGroup[int](cbreaker.WithThreshold(1))
+
+	_, err := group.Try("host-a", func() (int, error) {
+		return 0, errors.New("error")
+	})
+	require.Error(t, err)
+	require.Equal(t, cbreaker.StateOpen, group.State("host-a"))
+
+	// An unrelated key is unaffected by host-a's failure.
+	_, err = group.Try("host-b", func() (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, cbreaker.StateClosed, group.State("host-b"))
+
+	require.Equal(t, cbreaker.StateClosed, group.State("host-c"))
+
+	require.Equal(t, map[string]cbreaker.State{
+		"host-a": cbreaker.StateOpen,
+		"host-b": cbreaker.StateClosed,
+	}, group.Snapshot())
+
+	group.Reset("host-a")
+	require.Equal(t, cbreaker.StateClosed, group.State("host-a"))
+}
+
+func TestGroupTryContextAndBreakerAccessor(t *testing.T) {
+	t.Parallel()
+
+	group := cbreaker.NewGroup[int](cbreaker.WithThreshold(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	_, err := group.TryContext(ctx, "host-a", func(context.Context) (int, error) {
+		called = true
+		return 0, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, called)
+
+	_, err = group.TryContext(context.Background(), "host-a", func(context.Context) (int, error) {
+		return 0, errors.New("error")
+	})
+	require.Error(t, err)
+	require.Equal(t, cbreaker.StateOpen, group.State("host-a"))
+
+	// Breaker exposes the per-key Breaker directly; resetting through it
+	// affects the same circuit that Group.State reports.
+	group.Breaker("host-a").Reset()
+	require.Equal(t, cbreaker.StateClosed, group.State("host-a"))
+}
@@ -0,0 +1,94 @@
+// Package metrics adapts cbreaker.Breaker lifecycle events to Prometheus
+// metrics via the cbreaker.Observer interface.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/piotrpersona/cbreaker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PromCollector is a prometheus.Collector fed by a Breaker's lifecycle
+// events. It exposes:
+//
+//   - cbreaker_state{name="..."} gauge with the current cbreaker.State
+//   - cbreaker_requests_total{name="...",result="success|failure|short_circuit"} counter
+//   - cbreaker_state_transitions_total{name="..."} counter
+type PromCollector struct {
+	stateDesc       *prometheus.Desc
+	requestsDesc    *prometheus.Desc
+	transitionsDesc *prometheus.Desc
+
+	mu          sync.Mutex
+	state       cbreaker.State
+	requests    map[cbreaker.Result]uint64
+	transitions uint64
+}
+
+// NewPromCollector attaches a PromCollector to breaker as an Observer and
+// returns it as a prometheus.Collector ready to be registered with a
+// prometheus.Registerer. Metrics are labeled with breaker.Name().
+func NewPromCollector[T any](breaker *cbreaker.Breaker[T]) prometheus.Collector {
+	labels := prometheus.Labels{"name": breaker.Name()}
+	c := &PromCollector{
+		stateDesc: prometheus.NewDesc(
+			"cbreaker_state",
+			"Current circuit breaker state (0=Open, 1=Closed, 2=HalfOpen).",
+			nil, labels,
+		),
+		requestsDesc: prometheus.NewDesc(
+			"cbreaker_requests_total",
+			"Total requests observed by the circuit breaker, by result.",
+			[]string{"result"}, labels,
+		),
+		transitionsDesc: prometheus.NewDesc(
+			"cbreaker_state_transitions_total",
+			"Total circuit breaker state transitions.",
+			nil, labels,
+		),
+		state:    breaker.State(),
+		requests: make(map[cbreaker.Result]uint64),
+	}
+	breaker.Observe(c)
+	return c
+}
+
+// OnRequest implements cbreaker.Observer.
+func (c *PromCollector) OnRequest(result cbreaker.Result) {
+	c.mu.Lock()
+	c.requests[result]++
+	c.mu.Unlock()
+}
+
+// OnStateChange implements cbreaker.Observer.
+func (c *PromCollector) OnStateChange(_, to cbreaker.State) {
+	c.mu.Lock()
+	c.state = to
+	c.transitions++
+	c.mu.Unlock()
+}
+
+// OnShortCircuit implements cbreaker.Observer. Short-circuited calls are
+// already reflected in cbreaker_requests_total via OnRequest, so this is a
+// no-op hook kept for observers (e.g. tracing) that need the distinct event.
+func (c *PromCollector) OnShortCircuit() {}
+
+// Describe implements prometheus.Collector.
+func (c *PromCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.stateDesc
+	ch <- c.requestsDesc
+	ch <- c.transitionsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *PromCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(c.stateDesc, prometheus.GaugeValue, float64(c.state))
+	ch <- prometheus.MustNewConstMetric(c.transitionsDesc, prometheus.CounterValue, float64(c.transitions))
+	for _, result := range []cbreaker.Result{cbreaker.ResultSuccess, cbreaker.ResultFailure, cbreaker.ResultShortCircuit} {
+		ch <- prometheus.MustNewConstMetric(c.requestsDesc, prometheus.CounterValue, float64(c.requests[result]), result.String())
+	}
+}
@@ -0,0 +1,72 @@
+package metrics_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/piotrpersona/cbreaker"
+	"github.com/piotrpersona/cbreaker/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromCollector(t *testing.T) {
+	t.Parallel()
+
+	breaker := cbreaker.NewBreaker[int](
+		cbreaker.WithName("test-breaker"),
+		cbreaker.WithThreshold(1),
+	)
+	collector := metrics.NewPromCollector(breaker)
+
+	_, err := breaker.Try(func() (int, error) { return 0, nil })
+	require.NoError(t, err)
+	_, err = breaker.Try(func() (int, error) { return 0, errors.New("error") })
+	require.Error(t, err)
+	require.Equal(t, cbreaker.StateOpen, breaker.State())
+
+	_, err = breaker.Try(func() (int, error) { return 0, nil })
+	require.Error(t, err)
+
+	metricFamilies := gather(t, collector)
+
+	state := findMetric(t, metricFamilies, "cbreaker_state")
+	require.Equal(t, float64(cbreaker.StateOpen), state.GetGauge().GetValue())
+
+	transitions := findMetric(t, metricFamilies, "cbreaker_state_transitions_total")
+	require.Equal(t, float64(1), transitions.GetCounter().GetValue())
+
+	requestsFamily := findFamily(t, metricFamilies, "cbreaker_requests_total")
+	require.Len(t, requestsFamily.GetMetric(), 3)
+}
+
+func gather(t *testing.T, collector prometheus.Collector) []*dto.MetricFamily {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+	metricFamilies, err := registry.Gather()
+	require.NoError(t, err)
+	return metricFamilies
+}
+
+func findFamily(t *testing.T, families []*dto.MetricFamily, name string) *dto.MetricFamily {
+	t.Helper()
+
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+	t.Fatalf("metric family %q not found", name)
+	return nil
+}
+
+func findMetric(t *testing.T, families []*dto.MetricFamily, name string) *dto.Metric {
+	t.Helper()
+
+	family := findFamily(t, families, name)
+	require.Len(t, family.GetMetric(), 1)
+	return family.GetMetric()[0]
+}